@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+)
+
+// lineRef locates a single line within its source file so the original
+// text can be recovered on demand instead of being kept in memory.
+type lineRef struct {
+	Offset int64
+	Length int
+}
+
+// HashFileData is the streaming counterpart of FileData: instead of the
+// raw line text it keeps only a fingerprint per distinct line, plus the
+// on-disk location of every occurrence so matching lines can be
+// materialized again at display time.
+type HashFileData struct {
+	Path   string
+	Hashes map[uint64][]lineRef
+}
+
+// fingerprint returns a 64-bit FNV-1a hash of a line. Collisions are
+// possible (two different lines hashing the same); -collision-check
+// exists precisely to catch and discard those.
+func fingerprint(line string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(line))
+	return h.Sum64()
+}
+
+// readLinesHashIndex streams filename once, recording the fingerprint and
+// on-disk offset/length of every non-empty line without holding the line
+// text itself in memory. This keeps peak memory proportional to the
+// number of lines (one fixed-size entry per occurrence) rather than to the
+// size of their text.
+func readLinesHashIndex(filename string) (HashFileData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return HashFileData{}, err
+	}
+	defer file.Close()
+
+	data := HashFileData{
+		Path:   filename,
+		Hashes: make(map[uint64][]lineRef),
+	}
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadString('\n')
+		lineLen := len(line)
+		trimmed := line
+		if len(trimmed) > 0 && trimmed[len(trimmed)-1] == '\n' {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if len(trimmed) > 0 && trimmed[len(trimmed)-1] == '\r' {
+			// Match readLines/bufio.Scanner's ScanLines, which also
+			// strips a trailing \r so CRLF and LF inputs hash the same.
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if len(trimmed) > 0 {
+			h := fingerprint(trimmed)
+			data.Hashes[h] = append(data.Hashes[h], lineRef{Offset: offset, Length: len(trimmed)})
+		}
+		offset += int64(lineLen)
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return HashFileData{}, err
+		}
+	}
+
+	return data, nil
+}
+
+// materializeLine seeks into path and reads back the line text recorded
+// at ref, reconstructing the original string on demand. It opens path on
+// every call; callers materializing many lines from the same file should
+// use lineMaterializer instead to avoid repeated open/close overhead.
+func materializeLine(path string, ref lineRef) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buf := make([]byte, ref.Length)
+	if _, err := file.ReadAt(buf, ref.Offset); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// lineMaterializer caches one open file handle per path so that
+// materializing many lines from the same file - the common case when
+// displaying hash-mode results - costs one open instead of one per line.
+type lineMaterializer struct {
+	files map[string]*os.File
+}
+
+func newLineMaterializer() *lineMaterializer {
+	return &lineMaterializer{files: make(map[string]*os.File)}
+}
+
+func (m *lineMaterializer) read(path string, ref lineRef) (string, error) {
+	file, ok := m.files[path]
+	if !ok {
+		var err error
+		file, err = os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		m.files[path] = file
+	}
+
+	buf := make([]byte, ref.Length)
+	if _, err := file.ReadAt(buf, ref.Offset); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// Close releases every file handle opened by the materializer.
+func (m *lineMaterializer) Close() {
+	for _, file := range m.files {
+		file.Close()
+	}
+}
+
+// findCommonLinesHash returns the fingerprints present in every file.
+func findCommonLinesHash(files []HashFileData) []uint64 {
+	if len(files) == 0 {
+		return nil
+	}
+
+	common := make(map[uint64]bool)
+	for h := range files[0].Hashes {
+		common[h] = true
+	}
+	for i := 1; i < len(files); i++ {
+		next := make(map[uint64]bool)
+		for h := range common {
+			if _, ok := files[i].Hashes[h]; ok {
+				next[h] = true
+			}
+		}
+		common = next
+	}
+
+	var result []uint64
+	for h := range common {
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// findUniqueLinesHash returns, per file, the fingerprints that don't
+// appear in any other file.
+func findUniqueLinesHash(files []HashFileData) [][]uint64 {
+	result := make([][]uint64, len(files))
+
+	for i, file := range files {
+		var unique []uint64
+		for h := range file.Hashes {
+			isUnique := true
+			for j, other := range files {
+				if i == j {
+					continue
+				}
+				if _, ok := other.Hashes[h]; ok {
+					isUnique = false
+					break
+				}
+			}
+			if isUnique {
+				unique = append(unique, h)
+			}
+		}
+		sort.Slice(unique, func(a, b int) bool { return unique[a] < unique[b] })
+		result[i] = unique
+	}
+
+	return result
+}
+
+// findPartiallySharedLinesHash returns fingerprints that appear in more
+// than one file but not all of them, mapping each to the indices of the
+// files it appears in.
+func findPartiallySharedLinesHash(files []HashFileData) map[uint64][]int {
+	hashToFiles := make(map[uint64][]int)
+	for i, file := range files {
+		for h := range file.Hashes {
+			hashToFiles[h] = append(hashToFiles[h], i)
+		}
+	}
+
+	result := make(map[uint64][]int)
+	for h, indices := range hashToFiles {
+		if len(indices) > 1 && len(indices) < len(files) {
+			result[h] = indices
+		}
+	}
+	return result
+}
+
+// verifyNoCollision re-reads the actual text backing h in every file that
+// is supposed to share it and reports false if any of them disagree,
+// meaning h is a hash collision rather than a genuine shared line.
+func verifyNoCollision(m *lineMaterializer, files []HashFileData, h uint64, indices []int) (string, bool) {
+	var text string
+	for n, idx := range indices {
+		refs := files[idx].Hashes[h]
+		if len(refs) == 0 {
+			return "", false
+		}
+		candidate, err := m.read(files[idx].Path, refs[0])
+		if err != nil {
+			return "", false
+		}
+		if n == 0 {
+			text = candidate
+		} else if candidate != text {
+			return "", false
+		}
+	}
+	return text, true
+}
+
+// showCommonLinesHash is the -hash counterpart of showCommonLines: it
+// operates on fingerprints and materializes the underlying text only for
+// the common lines; all of them are read back, since they are sorted by
+// text before the display limit is applied.
+func showCommonLinesHash(files []HashFileData, config DiffViewerConfig) {
+	commonHashes := findCommonLinesHash(files)
+
+	if len(commonHashes) == 0 {
+		fmt.Printf("No common lines found across all %d files\n", len(files))
+		return
+	}
+
+	allIndices := make([]int, len(files))
+	for i := range files {
+		allIndices[i] = i
+	}
+
+	m := newLineMaterializer()
+	defer m.Close()
+
+	var lines []string
+	skipped := 0
+	for _, h := range commonHashes {
+		if config.CollisionCheck {
+			text, ok := verifyNoCollision(m, files, h, allIndices)
+			if !ok {
+				skipped++
+				continue
+			}
+			lines = append(lines, text)
+			continue
+		}
+		text, _ := m.read(files[0].Path, files[0].Hashes[h][0])
+		lines = append(lines, text)
+	}
+	sort.Strings(lines)
+
+	fmt.Printf("Lines common to all %d files:\n", len(files))
+	for i, file := range files {
+		if i == len(files)-1 {
+			fmt.Printf("  %s\n", file.Path)
+		} else {
+			fmt.Printf("  %s,\n", file.Path)
+		}
+	}
+	fmt.Printf("\nFound %d common lines", len(lines))
+	if config.CollisionCheck && skipped > 0 {
+		fmt.Printf(" (%d hash collisions discarded)", skipped)
+	}
+
+	displayLines := lines
+	if !config.ShowFull && len(lines) > config.MaxLines {
+		displayLines = lines[:config.MaxLines]
+		fmt.Printf(" (showing first %d):\n\n", config.MaxLines)
+	} else {
+		fmt.Printf(":\n\n")
+	}
+
+	for _, line := range displayLines {
+		fmt.Println(line)
+	}
+
+	if !config.ShowFull && len(lines) > config.MaxLines {
+		fmt.Printf("\n... and %d more lines (use -full or -f to see all)\n", len(lines)-config.MaxLines)
+	}
+}
+
+// showDifferentLinesHash is the -hash counterpart of showDifferentLines.
+func showDifferentLinesHash(files []HashFileData, config DiffViewerConfig) {
+	uniqueHashes := findUniqueLinesHash(files)
+
+	total := 0
+	for _, hashes := range uniqueHashes {
+		total += len(hashes)
+	}
+	if total == 0 {
+		fmt.Printf("No unique lines found - all files have identical content\n")
+		return
+	}
+	fmt.Printf("Lines unique to each file (total: %d unique lines):\n\n", total)
+
+	m := newLineMaterializer()
+	defer m.Close()
+
+	for i, file := range files {
+		hashes := uniqueHashes[i]
+		if len(hashes) == 0 {
+			fmt.Printf("No unique lines in %s\n\n", file.Path)
+			continue
+		}
+
+		var lines []string
+		for _, h := range hashes {
+			text, _ := m.read(file.Path, file.Hashes[h][0])
+			lines = append(lines, text)
+		}
+		sort.Strings(lines)
+
+		fmt.Printf("Lines only in %s (%d lines", file.Path, len(lines))
+		displayLines := lines
+		if !config.ShowFull && len(lines) > config.MaxLines {
+			displayLines = lines[:config.MaxLines]
+			fmt.Printf(", showing first %d):\n", config.MaxLines)
+		} else {
+			fmt.Printf("):\n")
+		}
+		for _, line := range displayLines {
+			fmt.Printf("  %s\n", line)
+		}
+		if !config.ShowFull && len(lines) > config.MaxLines {
+			fmt.Printf("  ... and %d more lines\n", len(lines)-config.MaxLines)
+		}
+		fmt.Println()
+	}
+
+	if len(files) > 2 {
+		showPartiallySharedHash(files, config, m)
+	}
+}
+
+// showPartiallySharedHash is the -hash counterpart of the "shared by some
+// files (but not all)" section that showDifferentLines prints for >2
+// files. When -collision-check is set, each candidate hash is verified
+// the same way showCommonLinesHash verifies common hashes, since a
+// collision here would otherwise misreport which files actually share a
+// line.
+func showPartiallySharedHash(files []HashFileData, config DiffViewerConfig, m *lineMaterializer) {
+	partiallyShared := findPartiallySharedLinesHash(files)
+	if len(partiallyShared) == 0 {
+		return
+	}
+
+	type entry struct {
+		text    string
+		indices []int
+	}
+	var entries []entry
+	skipped := 0
+	for h, indices := range partiallyShared {
+		if config.CollisionCheck {
+			text, ok := verifyNoCollision(m, files, h, indices)
+			if !ok {
+				skipped++
+				continue
+			}
+			entries = append(entries, entry{text, indices})
+			continue
+		}
+		text, _ := m.read(files[indices[0]].Path, files[indices[0]].Hashes[h][0])
+		entries = append(entries, entry{text, indices})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].text < entries[j].text })
+
+	fmt.Printf("Lines shared by some files (but not all)")
+	if config.CollisionCheck && skipped > 0 {
+		fmt.Printf(" (%d hash collisions discarded)", skipped)
+	}
+	fmt.Printf(":\n")
+
+	displayEntries := entries
+	if !config.ShowFull && len(entries) > config.MaxLines {
+		displayEntries = entries[:config.MaxLines]
+		fmt.Printf("(showing first %d of %d):\n", config.MaxLines, len(entries))
+	}
+
+	for _, e := range displayEntries {
+		fmt.Printf("  \"%s\" appears in:", e.text)
+		for _, idx := range e.indices {
+			fmt.Printf(" %s", files[idx].Path)
+		}
+		fmt.Println()
+	}
+
+	if !config.ShowFull && len(entries) > config.MaxLines {
+		fmt.Printf("  ... and %d more partially shared lines\n", len(entries)-config.MaxLines)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReadLinesHashIndex(t *testing.T) {
+	testFile := "test_hash_read.txt"
+	createTestFile(t, testFile, []string{"line1", "line2", "line1"})
+	defer cleanupTestFiles(testFile)
+
+	data, err := readLinesHashIndex(testFile)
+	if err != nil {
+		t.Fatalf("readLinesHashIndex failed: %v", err)
+	}
+
+	h1 := fingerprint("line1")
+	h2 := fingerprint("line2")
+
+	if len(data.Hashes[h1]) != 2 {
+		t.Errorf("expected 2 occurrences of line1, got %d", len(data.Hashes[h1]))
+	}
+	if len(data.Hashes[h2]) != 1 {
+		t.Errorf("expected 1 occurrence of line2, got %d", len(data.Hashes[h2]))
+	}
+}
+
+func TestReadLinesHashIndexStripsCRLF(t *testing.T) {
+	testFile := "test_hash_crlf.txt"
+	if err := os.WriteFile(testFile, []byte("hello\r\nworld\r\n"), 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	defer cleanupTestFiles(testFile)
+
+	data, err := readLinesHashIndex(testFile)
+	if err != nil {
+		t.Fatalf("readLinesHashIndex failed: %v", err)
+	}
+
+	if _, ok := data.Hashes[fingerprint("hello")]; !ok {
+		t.Error("expected 'hello' (without trailing \\r) to be fingerprinted, matching readLines")
+	}
+	if _, ok := data.Hashes[fingerprint("hello\r")]; ok {
+		t.Error("did not expect 'hello\\r' to be fingerprinted")
+	}
+
+	ref := data.Hashes[fingerprint("world")][0]
+	text, err := materializeLine(testFile, ref)
+	if err != nil {
+		t.Fatalf("materializeLine failed: %v", err)
+	}
+	if text != "world" {
+		t.Errorf("expected materialized text %q, got %q", "world", text)
+	}
+}
+
+func TestMaterializeLine(t *testing.T) {
+	testFile := "test_hash_materialize.txt"
+	createTestFile(t, testFile, []string{"apple", "banana"})
+	defer cleanupTestFiles(testFile)
+
+	data, err := readLinesHashIndex(testFile)
+	if err != nil {
+		t.Fatalf("readLinesHashIndex failed: %v", err)
+	}
+
+	h := fingerprint("banana")
+	ref := data.Hashes[h][0]
+	text, err := materializeLine(testFile, ref)
+	if err != nil {
+		t.Fatalf("materializeLine failed: %v", err)
+	}
+	if text != "banana" {
+		t.Errorf("expected %q, got %q", "banana", text)
+	}
+}
+
+func TestFindCommonLinesHash(t *testing.T) {
+	files := []HashFileData{
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("apple"):  {{0, 5}},
+			fingerprint("banana"): {{6, 6}},
+		}},
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("banana"): {{0, 6}},
+			fingerprint("cherry"): {{7, 6}},
+		}},
+	}
+
+	common := findCommonLinesHash(files)
+	expected := []uint64{fingerprint("banana")}
+	if !reflect.DeepEqual(common, expected) {
+		t.Errorf("expected %v, got %v", expected, common)
+	}
+}
+
+func TestFindUniqueLinesHash(t *testing.T) {
+	files := []HashFileData{
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("apple"):  {{0, 5}},
+			fingerprint("banana"): {{6, 6}},
+		}},
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("banana"): {{0, 6}},
+			fingerprint("cherry"): {{7, 6}},
+		}},
+	}
+
+	unique := findUniqueLinesHash(files)
+	if len(unique[0]) != 1 || unique[0][0] != fingerprint("apple") {
+		t.Errorf("expected only 'apple' unique to file1, got %v", unique[0])
+	}
+	if len(unique[1]) != 1 || unique[1][0] != fingerprint("cherry") {
+		t.Errorf("expected only 'cherry' unique to file2, got %v", unique[1])
+	}
+}
+
+func TestFindPartiallySharedLinesHash(t *testing.T) {
+	files := []HashFileData{
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("apple"):  {{0, 5}},
+			fingerprint("banana"): {{6, 6}},
+			fingerprint("cherry"): {{13, 6}},
+		}},
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("banana"): {{0, 6}},
+			fingerprint("cherry"): {{7, 6}},
+			fingerprint("date"):   {{14, 4}},
+		}},
+		{Hashes: map[uint64][]lineRef{
+			fingerprint("cherry"):     {{0, 6}},
+			fingerprint("date"):       {{7, 4}},
+			fingerprint("elderberry"): {{12, 10}},
+		}},
+	}
+
+	partial := findPartiallySharedLinesHash(files)
+
+	if indices, ok := partial[fingerprint("banana")]; !ok || !reflect.DeepEqual(indices, []int{0, 1}) {
+		t.Errorf("expected 'banana' in files [0,1], got %v (ok=%v)", indices, ok)
+	}
+	if indices, ok := partial[fingerprint("date")]; !ok || !reflect.DeepEqual(indices, []int{1, 2}) {
+		t.Errorf("expected 'date' in files [1,2], got %v (ok=%v)", indices, ok)
+	}
+	if _, ok := partial[fingerprint("cherry")]; ok {
+		t.Error("'cherry' appears in all 3 files and should not be partially shared")
+	}
+	if _, ok := partial[fingerprint("apple")]; ok {
+		t.Error("'apple' appears in only 1 file and should not be partially shared")
+	}
+}
+
+func TestVerifyNoCollisionDetectsMismatch(t *testing.T) {
+	file1 := "test_collision1.txt"
+	file2 := "test_collision2.txt"
+	createTestFile(t, file1, []string{"apple"})
+	createTestFile(t, file2, []string{"not-apple"})
+	defer cleanupTestFiles(file1, file2)
+
+	data1, _ := readLinesHashIndex(file1)
+	data2, _ := readLinesHashIndex(file2)
+
+	h := fingerprint("apple")
+	// Simulate a collision: file2 claims to have a line under the same hash
+	// even though the actual text differs.
+	data2.Hashes[h] = []lineRef{{0, len("not-apple")}}
+
+	files := []HashFileData{data1, data2}
+	m := newLineMaterializer()
+	defer m.Close()
+	_, ok := verifyNoCollision(m, files, h, []int{0, 1})
+	if ok {
+		t.Error("expected verifyNoCollision to detect mismatched text")
+	}
+}
@@ -17,17 +17,34 @@ type DiffViewerConfig struct {
 	ShowDiff  bool
 	ShowFull  bool
 	MaxLines  int
+	Unified        bool
+	Context        int
+	HashMode       bool
+	CollisionCheck bool
+	CountMode      bool
+	Format         string
+	Cluster        float64
+	Matrix         bool
 }
 var config = DiffViewerConfig{}
 func main() {
-	
+
 	flag.BoolVar(&config.ShowDiff, "diff", false, "Show different lines instead of common lines")
 	flag.BoolVar(&config.ShowDiff, "d", false, "Show different lines instead of common lines (shorthand)")
 	flag.BoolVar(&config.ShowFull, "full", false, "Show full output without truncation")
 	flag.BoolVar(&config.ShowFull, "f", false, "Show full output without truncation (shorthand)")
 	flag.IntVar(&config.MaxLines, "limit", 20, "Maximum number of lines to show per section (use with -full to override)")
 	flag.IntVar(&config.MaxLines, "l", 20, "Maximum number of lines to show per section (shorthand)")
-	
+	flag.BoolVar(&config.Unified, "unified", false, "Show a line-ordered unified diff instead of set-based common/unique lines")
+	flag.BoolVar(&config.Unified, "u", false, "Show a line-ordered unified diff (shorthand)")
+	flag.IntVar(&config.Context, "U", 3, "Number of context lines to show around each change in -unified mode")
+	flag.BoolVar(&config.HashMode, "hash", false, "Stream files as line fingerprints instead of loading raw lines, for files too large to fit in memory")
+	flag.BoolVar(&config.CollisionCheck, "collision-check", false, "In -hash mode, re-read and verify candidate lines to rule out hash collisions")
+	flag.BoolVar(&config.CountMode, "count", false, "Track per-line occurrence counts instead of collapsing duplicates")
+	flag.StringVar(&config.Format, "format", "text", "Output format: text, json, ndjson, or csv")
+	flag.Float64Var(&config.Cluster, "cluster", 0, "Print the Jaccard similarity matrix for the given files and group files whose similarity meets this threshold (0 disables clustering)")
+	flag.BoolVar(&config.Matrix, "matrix", false, "Print an N×N Jaccard similarity matrix for the given files instead of comparing them directly")
+
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <file1> <file2> [file3] [file4] ...\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Finds common or different lines between multiple files\n\n")
@@ -39,40 +56,131 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s -limit 50 file1.txt file2.txt          # Show first 50 lines\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -diff file1.txt file2.txt              # Show first 20 unique lines per file\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -d -f file1.txt file2.txt              # Show all unique lines\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -u file1.txt file2.txt                 # Show a unified diff\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -u -U 5 file1.txt file2.txt            # Unified diff with 5 lines of context\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -hash file1.txt file2.txt              # Compare huge files via line fingerprints\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -count file1.txt file2.txt             # Show per-line occurrence counts\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -format json file1.txt file2.txt       # Emit a machine-readable report\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s dirA/ dirB/                             # Compare two directory trees by relative path\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -cluster 0.8 '*.log'                   # Similarity matrix and clusters for a glob\n", os.Args[0])
 	}
 	
 	flag.Parse()
-	
-	if flag.NArg() < 2 {
-		fmt.Fprintf(os.Stderr, "Error: At least 2 files are required\n\n")
+
+	if flag.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Error: At least 2 files (or one glob pattern matching 2+ files) are required\n\n")
 		flag.Usage()
 		os.Exit(1)
 	}
 
 	filePaths := flag.Args()
-	
+
+	if len(filePaths) == 2 && isDir(filePaths[0]) && isDir(filePaths[1]) {
+		result, err := compareDirectories(filePaths[0], filePaths[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing directories: %v\n", err)
+			os.Exit(1)
+		}
+		showDirCompare(result, config.Context)
+		return
+	}
+
+	expanded, err := expandGlobs(filePaths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error expanding globs: %v\n", err)
+		os.Exit(1)
+	}
+	filePaths = expanded
+
+	if len(filePaths) < 2 {
+		fmt.Fprintf(os.Stderr, "Error: At least 2 files are required\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if config.Matrix || config.Cluster > 0 {
+		var matrixFiles []FileData
+		for _, path := range filePaths {
+			lines, err := readLines(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			lineMap := make(map[string]bool)
+			for _, line := range lines {
+				lineMap[line] = true
+			}
+			matrixFiles = append(matrixFiles, FileData{Path: path, Lines: lineMap})
+		}
+		showSimilarityMatrix(matrixFiles, config.Cluster)
+		return
+	}
+
+	if config.HashMode {
+		var hashFiles []HashFileData
+		for _, path := range filePaths {
+			data, err := readLinesHashIndex(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			hashFiles = append(hashFiles, data)
+		}
+
+		if config.ShowDiff {
+			showDifferentLinesHash(hashFiles, config)
+		} else {
+			showCommonLinesHash(hashFiles, config)
+		}
+		return
+	}
+
 	// Read all files
 	var files []FileData
+	var rawLines [][]string
 	for _, path := range filePaths {
 		lines, err := readLines(path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
 			os.Exit(1)
 		}
-		
+		rawLines = append(rawLines, lines)
+
 		// Convert slice to map for efficient lookup
 		lineMap := make(map[string]bool)
 		for _, line := range lines {
 			lineMap[line] = true
 		}
-		
+
 		files = append(files, FileData{
 			Path:  path,
 			Lines: lineMap,
 		})
 	}
 
-	if config.ShowDiff {
+	if config.Format != "text" {
+		reporter, err := reporterFor(config.Format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := reporter.Write(buildReport(files)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(1)
+		}
+	} else if config.Unified {
+		showUnifiedDiff(files, rawLines, config.Context)
+	} else if config.CountMode {
+		var countFiles []CountFileData
+		for i, file := range files {
+			countFiles = append(countFiles, CountFileData{Path: file.Path, Counts: countLinesMap(rawLines[i])})
+		}
+		if config.ShowDiff {
+			showDifferentLinesMulti(countFiles, config)
+		} else {
+			showCommonLinesMulti(countFiles, config)
+		}
+	} else if config.ShowDiff {
 		showDifferentLines(files, config)
 	} else {
 		showCommonLines(files, config)
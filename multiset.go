@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CountFileData is the multiset counterpart of FileData: instead of
+// collapsing repeated lines into a single boolean presence, it keeps how
+// many times each line occurs.
+type CountFileData struct {
+	Path   string
+	Counts map[string]int
+}
+
+// countLinesMap turns an ordered line slice into a line -> occurrence
+// count map, preserving multiplicity that map[string]bool would discard.
+func countLinesMap(lines []string) map[string]int {
+	counts := make(map[string]int)
+	for _, line := range lines {
+		counts[line]++
+	}
+	return counts
+}
+
+// findCommonLinesMulti returns, for every line present in all files, the
+// multiset intersection count: min(count_i) across files.
+func findCommonLinesMulti(files []CountFileData) map[string]int {
+	if len(files) == 0 {
+		return nil
+	}
+
+	result := make(map[string]int)
+	for line, count := range files[0].Counts {
+		result[line] = count
+	}
+
+	for i := 1; i < len(files); i++ {
+		next := make(map[string]int)
+		for line, count := range result {
+			if c, ok := files[i].Counts[line]; ok {
+				if c < count {
+					next[line] = c
+				} else {
+					next[line] = count
+				}
+			}
+		}
+		result = next
+	}
+
+	return result
+}
+
+// findUniqueLinesMulti returns, per file, how many occurrences of each
+// line are not matched by any occurrence in the other files: for line L
+// in file i, max(0, count_i(L) - max_{j!=i} count_j(L)).
+func findUniqueLinesMulti(files []CountFileData) []map[string]int {
+	result := make([]map[string]int, len(files))
+
+	for i, file := range files {
+		unique := make(map[string]int)
+		for line, count := range file.Counts {
+			maxOther := 0
+			for j, other := range files {
+				if i == j {
+					continue
+				}
+				if c := other.Counts[line]; c > maxOther {
+					maxOther = c
+				}
+			}
+			if extra := count - maxOther; extra > 0 {
+				unique[line] = extra
+			}
+		}
+		result[i] = unique
+	}
+
+	return result
+}
+
+// findPartiallySharedLinesMulti returns lines that occur in more than one
+// file but not all of them, mapping each to its per-file occurrence count
+// (0 for files that don't contain the line).
+func findPartiallySharedLinesMulti(files []CountFileData) map[string][]int {
+	lineFiles := make(map[string]int)
+	for _, file := range files {
+		for line := range file.Counts {
+			lineFiles[line]++
+		}
+	}
+
+	result := make(map[string][]int)
+	for line, numFiles := range lineFiles {
+		if numFiles > 1 && numFiles < len(files) {
+			counts := make([]int, len(files))
+			for i, file := range files {
+				counts[i] = file.Counts[line]
+			}
+			result[line] = counts
+		}
+	}
+	return result
+}
+
+// formatCounts renders a line's per-file occurrence counts as
+// "line (×N in file1, ×M in file2)", skipping files with a zero count.
+func formatCounts(line string, files []CountFileData, counts []int) string {
+	out := line + " ("
+	first := true
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		if !first {
+			out += ", "
+		}
+		out += fmt.Sprintf("×%d in %s", count, files[i].Path)
+		first = false
+	}
+	return out + ")"
+}
+
+func showCommonLinesMulti(files []CountFileData, config DiffViewerConfig) {
+	common := findCommonLinesMulti(files)
+
+	if len(common) == 0 {
+		fmt.Printf("No common lines found across all %d files\n", len(files))
+		return
+	}
+
+	var lines []string
+	for line := range common {
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	fmt.Printf("Lines common to all %d files:\n", len(files))
+	for i, file := range files {
+		if i == len(files)-1 {
+			fmt.Printf("  %s\n", file.Path)
+		} else {
+			fmt.Printf("  %s,\n", file.Path)
+		}
+	}
+	fmt.Printf("\nFound %d common lines", len(lines))
+
+	displayLines := lines
+	if !config.ShowFull && len(lines) > config.MaxLines {
+		displayLines = lines[:config.MaxLines]
+		fmt.Printf(" (showing first %d):\n\n", config.MaxLines)
+	} else {
+		fmt.Printf(":\n\n")
+	}
+
+	for _, line := range displayLines {
+		counts := make([]int, len(files))
+		for i, file := range files {
+			counts[i] = file.Counts[line]
+		}
+		fmt.Println(formatCounts(line, files, counts))
+	}
+
+	if !config.ShowFull && len(lines) > config.MaxLines {
+		fmt.Printf("\n... and %d more lines (use -full or -f to see all)\n", len(lines)-config.MaxLines)
+	}
+}
+
+func showDifferentLinesMulti(files []CountFileData, config DiffViewerConfig) {
+	uniquePerFile := findUniqueLinesMulti(files)
+
+	total := 0
+	for _, unique := range uniquePerFile {
+		total += len(unique)
+	}
+	if total == 0 {
+		fmt.Printf("No unique lines found - all files have identical content\n")
+		return
+	}
+	fmt.Printf("Lines unique to each file (total: %d unique lines):\n\n", total)
+
+	for i, file := range files {
+		unique := uniquePerFile[i]
+		if len(unique) == 0 {
+			fmt.Printf("No unique lines in %s\n\n", file.Path)
+			continue
+		}
+
+		var lines []string
+		for line := range unique {
+			lines = append(lines, line)
+		}
+		sort.Strings(lines)
+
+		fmt.Printf("Lines only in %s (%d lines", file.Path, len(lines))
+		displayLines := lines
+		if !config.ShowFull && len(lines) > config.MaxLines {
+			displayLines = lines[:config.MaxLines]
+			fmt.Printf(", showing first %d):\n", config.MaxLines)
+		} else {
+			fmt.Printf("):\n")
+		}
+		for _, line := range displayLines {
+			fmt.Printf("  %s (×%d)\n", line, unique[line])
+		}
+		if !config.ShowFull && len(lines) > config.MaxLines {
+			fmt.Printf("  ... and %d more lines\n", len(lines)-config.MaxLines)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountLinesMap(t *testing.T) {
+	counts := countLinesMap([]string{"a", "b", "a", "a"})
+	expected := map[string]int{"a": 3, "b": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected %v, got %v", expected, counts)
+	}
+}
+
+func TestFindCommonLinesMulti(t *testing.T) {
+	files := []CountFileData{
+		{Path: "file1.txt", Counts: map[string]int{"apple": 5, "banana": 2}},
+		{Path: "file2.txt", Counts: map[string]int{"apple": 1, "banana": 3}},
+	}
+
+	common := findCommonLinesMulti(files)
+	expected := map[string]int{"apple": 1, "banana": 2}
+	if !reflect.DeepEqual(common, expected) {
+		t.Errorf("expected %v, got %v", expected, common)
+	}
+}
+
+func TestFindUniqueLinesMulti(t *testing.T) {
+	files := []CountFileData{
+		{Path: "file1.txt", Counts: map[string]int{"apple": 5, "banana": 2}},
+		{Path: "file2.txt", Counts: map[string]int{"apple": 1, "cherry": 3}},
+	}
+
+	unique := findUniqueLinesMulti(files)
+
+	if got, want := unique[0]["apple"], 4; got != want {
+		t.Errorf("file1 unique apple: expected %d, got %d", want, got)
+	}
+	if got, want := unique[0]["banana"], 2; got != want {
+		t.Errorf("file1 unique banana: expected %d, got %d", want, got)
+	}
+	if _, ok := unique[1]["apple"]; ok {
+		t.Error("file2 should have no unique 'apple' occurrences (1 <= 5)")
+	}
+	if got, want := unique[1]["cherry"], 3; got != want {
+		t.Errorf("file2 unique cherry: expected %d, got %d", want, got)
+	}
+}
+
+func TestFindPartiallySharedLinesMulti(t *testing.T) {
+	files := []CountFileData{
+		{Path: "file1.txt", Counts: map[string]int{"apple": 2, "banana": 1}},
+		{Path: "file2.txt", Counts: map[string]int{"banana": 3, "cherry": 1}},
+		{Path: "file3.txt", Counts: map[string]int{"cherry": 2}},
+	}
+
+	partial := findPartiallySharedLinesMulti(files)
+
+	if counts, ok := partial["banana"]; !ok || !reflect.DeepEqual(counts, []int{1, 3, 0}) {
+		t.Errorf("expected banana counts [1,3,0], got %v (ok=%v)", counts, ok)
+	}
+	if counts, ok := partial["cherry"]; !ok || !reflect.DeepEqual(counts, []int{0, 1, 2}) {
+		t.Errorf("expected cherry counts [0,1,2], got %v (ok=%v)", counts, ok)
+	}
+	if _, ok := partial["apple"]; ok {
+		t.Error("apple only appears in one file and should not be partially shared")
+	}
+}
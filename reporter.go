@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// PartialShare is one line that appears in more than one file but not
+// all of them, alongside the files it was found in.
+type PartialShare struct {
+	Line    string   `json:"line"`
+	InFiles []string `json:"inFiles"`
+}
+
+// DiffReport is the structured, format-agnostic result of comparing a set
+// of files: every machine-readable writer (JSON, NDJSON, CSV) renders the
+// same report, so the schema stays stable across -format values.
+type DiffReport struct {
+	Files           []string            `json:"files"`
+	Common          []string            `json:"common"`
+	Unique          map[string][]string `json:"unique"`
+	PartiallyShared []PartialShare      `json:"partiallyShared"`
+}
+
+// buildReport runs the existing set-based comparisons and assembles their
+// results into the shared report schema.
+func buildReport(files []FileData) DiffReport {
+	report := DiffReport{
+		Unique: make(map[string][]string),
+	}
+	for _, file := range files {
+		report.Files = append(report.Files, file.Path)
+	}
+
+	report.Common = findCommonLines(files)
+
+	unique := findUniqueLines(files)
+	for i, file := range files {
+		report.Unique[file.Path] = unique[i]
+	}
+
+	partiallyShared := findPartiallySharedLines(files)
+	var partialLines []string
+	for line := range partiallyShared {
+		partialLines = append(partialLines, line)
+	}
+	sort.Strings(partialLines)
+	for _, line := range partialLines {
+		var inFiles []string
+		for _, idx := range partiallyShared[line] {
+			inFiles = append(inFiles, files[idx].Path)
+		}
+		report.PartiallyShared = append(report.PartiallyShared, PartialShare{Line: line, InFiles: inFiles})
+	}
+
+	return report
+}
+
+// Reporter renders a DiffReport in a specific output format. The default
+// text mode doesn't implement it directly (its layout is driven by the
+// ShowDiff/ShowFull/MaxLines display options rather than a fixed schema);
+// the machine-readable formats below all share this interface so adding
+// a new one only means writing a new Reporter.
+type Reporter interface {
+	Write(report DiffReport) error
+}
+
+// JSONReporter writes the report as a single JSON object matching
+// DiffReport's schema.
+type JSONReporter struct{}
+
+func (JSONReporter) Write(report DiffReport) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// ndjsonRecord is one line of NDJSON output: a single fact about the
+// comparison (a common line, a unique line, or a partially shared line),
+// so the stream can be piped into jq or a log pipeline without parsing a
+// whole-report object first.
+type ndjsonRecord struct {
+	Category string   `json:"category"`
+	Line     string   `json:"line"`
+	File     string   `json:"file,omitempty"`
+	InFiles  []string `json:"inFiles,omitempty"`
+}
+
+// NDJSONReporter writes the report as newline-delimited JSON, one record
+// per line of interest.
+type NDJSONReporter struct{}
+
+func (NDJSONReporter) Write(report DiffReport) error {
+	encoder := json.NewEncoder(os.Stdout)
+
+	for _, line := range report.Common {
+		if err := encoder.Encode(ndjsonRecord{Category: "common", Line: line}); err != nil {
+			return err
+		}
+	}
+	for _, path := range report.Files {
+		for _, line := range report.Unique[path] {
+			if err := encoder.Encode(ndjsonRecord{Category: "unique", Line: line, File: path}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, share := range report.PartiallyShared {
+		if err := encoder.Encode(ndjsonRecord{Category: "partial", Line: share.Line, InFiles: share.InFiles}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CSVReporter writes the report as CSV with a `line,category,file1,file2,...`
+// header, where each file column is a 0/1 presence flag for that line.
+type CSVReporter struct{}
+
+func (CSVReporter) Write(report DiffReport) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	header := append([]string{"line", "category"}, report.Files...)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	presence := func(inFiles []string) []string {
+		set := make(map[string]bool, len(inFiles))
+		for _, f := range inFiles {
+			set[f] = true
+		}
+		row := make([]string, len(report.Files))
+		for i, path := range report.Files {
+			if set[path] {
+				row[i] = "1"
+			} else {
+				row[i] = "0"
+			}
+		}
+		return row
+	}
+
+	for _, line := range report.Common {
+		row := append([]string{line, "common"}, presence(report.Files)...)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	for _, path := range report.Files {
+		for _, line := range report.Unique[path] {
+			row := append([]string{line, "unique"}, presence([]string{path})...)
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	for _, share := range report.PartiallyShared {
+		row := append([]string{share.Line, "partial"}, presence(share.InFiles)...)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reporterFor resolves a -format flag value to its Reporter, or an error
+// if the format isn't recognized.
+func reporterFor(format string) (Reporter, error) {
+	switch format {
+	case "json":
+		return JSONReporter{}, nil
+	case "ndjson":
+		return NDJSONReporter{}, nil
+	case "csv":
+		return CSVReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want json, ndjson, or csv)", format)
+	}
+}
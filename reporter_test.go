@@ -0,0 +1,63 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildReport(t *testing.T) {
+	files := []FileData{
+		{Path: "file1.txt", Lines: map[string]bool{"apple": true, "banana": true}},
+		{Path: "file2.txt", Lines: map[string]bool{"banana": true, "cherry": true}},
+	}
+
+	report := buildReport(files)
+
+	if !reflect.DeepEqual(report.Files, []string{"file1.txt", "file2.txt"}) {
+		t.Errorf("unexpected Files: %v", report.Files)
+	}
+	if !reflect.DeepEqual(report.Common, []string{"banana"}) {
+		t.Errorf("unexpected Common: %v", report.Common)
+	}
+	if !reflect.DeepEqual(report.Unique["file1.txt"], []string{"apple"}) {
+		t.Errorf("unexpected Unique[file1.txt]: %v", report.Unique["file1.txt"])
+	}
+	if !reflect.DeepEqual(report.Unique["file2.txt"], []string{"cherry"}) {
+		t.Errorf("unexpected Unique[file2.txt]: %v", report.Unique["file2.txt"])
+	}
+	if len(report.PartiallyShared) != 0 {
+		t.Errorf("expected no partially shared lines for 2 files, got %v", report.PartiallyShared)
+	}
+}
+
+func TestBuildReportPartiallyShared(t *testing.T) {
+	files := []FileData{
+		{Path: "file1.txt", Lines: map[string]bool{"apple": true, "banana": true}},
+		{Path: "file2.txt", Lines: map[string]bool{"banana": true, "cherry": true}},
+		{Path: "file3.txt", Lines: map[string]bool{"cherry": true}},
+	}
+
+	report := buildReport(files)
+
+	expected := []PartialShare{
+		{Line: "banana", InFiles: []string{"file1.txt", "file2.txt"}},
+		{Line: "cherry", InFiles: []string{"file2.txt", "file3.txt"}},
+	}
+	if !reflect.DeepEqual(report.PartiallyShared, expected) {
+		t.Errorf("expected %v, got %v", expected, report.PartiallyShared)
+	}
+}
+
+func TestReporterForUnknownFormat(t *testing.T) {
+	if _, err := reporterFor("xml"); err == nil {
+		t.Error("expected an error for an unrecognized format")
+	}
+}
+
+func TestReporterForKnownFormats(t *testing.T) {
+	for _, format := range []string{"json", "ndjson", "csv"} {
+		if _, err := reporterFor(format); err != nil {
+			t.Errorf("unexpected error for format %q: %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+)
+
+// diffOp is a single edit operation produced while walking the Myers trace
+// back from the final edit distance to the start.
+type diffOp struct {
+	Kind byte // ' ' (equal), '-' (delete from a), '+' (insert from b)
+	Line string
+}
+
+// myersDiff computes the edit script that turns a into b using Myers'
+// O(ND) shortest edit script algorithm. It returns the script as a
+// sequence of diffOps in a/b order, including the unchanged lines.
+func myersDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	// trace[d] is a snapshot of the V array as the sweep for edit distance d
+	// reads it (the values left by d-1), needed to walk the path back to
+	// front once we find the shortest script.
+	offset := max
+	v := make([]int, 2*max+1)
+	var trace [][]int
+
+	found := false
+	var foundD int
+	for d := 0; d <= max && !found; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset] // down: insert from b
+			} else {
+				x = v[k-1+offset] + 1 // right: delete from a
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[k+offset] = x
+
+			if x >= n && y >= m {
+				found = true
+				foundD = d
+				snapshot = make([]int, len(v))
+				copy(snapshot, v)
+				trace[d] = snapshot
+				break
+			}
+		}
+	}
+
+	// Walk the recorded trace backwards to reconstruct the edit script,
+	// then reverse it into forward order.
+	var ops []diffOp
+	x, y := n, m
+	for d := foundD; d > 0; d-- {
+		// trace[d] holds the V array exactly as it was read while sweeping
+		// edit distance d (the sweep's own writes use the opposite array
+		// parity, so they're invisible to reads within the same sweep).
+		vPrev := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && vPrev[k-1+offset] < vPrev[k+1+offset]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := vPrev[prevK+offset]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{Kind: ' ', Line: a[x]})
+		}
+
+		if x == prevX {
+			y--
+			ops = append(ops, diffOp{Kind: '+', Line: b[y]})
+		} else {
+			x--
+			ops = append(ops, diffOp{Kind: '-', Line: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, diffOp{Kind: ' ', Line: a[x]})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// hunk is a contiguous block of diffOps plus the starting line numbers (1
+// based) of each side, ready to be rendered as a unified-diff `@@` header.
+type hunk struct {
+	AStart, ALen int
+	BStart, BLen int
+	Ops          []diffOp
+}
+
+// buildHunks groups the edits in ops into hunks, padding each with up to
+// context lines of unchanged lines on either side and merging hunks whose
+// context would otherwise overlap.
+func buildHunks(ops []diffOp, context int) []hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var hunks []hunk
+
+	type pos struct{ a, b int }
+	starts := make([]pos, len(ops)+1)
+	a, b := 0, 0
+	for i, op := range ops {
+		starts[i] = pos{a, b}
+		switch op.Kind {
+		case ' ':
+			a++
+			b++
+		case '-':
+			a++
+		case '+':
+			b++
+		}
+	}
+	starts[len(ops)] = pos{a, b}
+
+	i := 0
+	for i < len(ops) {
+		if ops[i].Kind == ' ' {
+			i++
+			continue
+		}
+
+		// Start a new hunk. Back up to include leading context.
+		start := i
+		for k := 0; k < context && start > 0 && ops[start-1].Kind == ' '; k++ {
+			start--
+		}
+
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != ' ' {
+				end++
+				continue
+			}
+			// Look ahead: if there's another change within 2*context lines
+			// of unchanged lines, keep this hunk going instead of splitting.
+			run := 0
+			probe := end
+			for probe < len(ops) && ops[probe].Kind == ' ' {
+				run++
+				probe++
+			}
+			if probe < len(ops) && run <= 2*context {
+				end = probe
+				continue
+			}
+			if run > context {
+				end += context
+			} else {
+				end = probe
+			}
+			break
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		aLen := starts[end].a - starts[start].a
+		bLen := starts[end].b - starts[start].b
+
+		aStart := starts[start].a + 1
+		if aLen == 0 {
+			// GNU diff convention: a zero-length side reports the line
+			// number it would be inserted after, not the next line.
+			aStart = starts[start].a
+		}
+		bStart := starts[start].b + 1
+		if bLen == 0 {
+			bStart = starts[start].b
+		}
+
+		h := hunk{
+			AStart: aStart,
+			BStart: bStart,
+			ALen:   aLen,
+			BLen:   bLen,
+			Ops:    ops[start:end],
+		}
+		hunks = append(hunks, h)
+
+		i = end
+	}
+
+	return hunks
+}
+
+// printUnifiedDiff renders a and b as a standard unified diff between
+// pathA and pathB, with the given number of context lines around each
+// change. It writes nothing and returns false if the files are identical.
+func printUnifiedDiff(pathA, pathB string, a, b []string, context int) bool {
+	ops := myersDiff(a, b)
+	hunks := buildHunks(ops, context)
+	if len(hunks) == 0 {
+		return false
+	}
+
+	fmt.Printf("--- %s\n", pathA)
+	fmt.Printf("+++ %s\n", pathB)
+
+	for _, h := range hunks {
+		fmt.Printf("@@ -%d,%d +%d,%d @@\n", h.AStart, h.ALen, h.BStart, h.BLen)
+		for _, op := range h.Ops {
+			fmt.Printf("%c%s\n", op.Kind, op.Line)
+		}
+	}
+
+	return true
+}
+
+// showUnifiedDiff implements the -u/--unified output mode. For exactly two
+// files it prints a single unified diff; for more than two it falls back to
+// pairwise diffs of each file against the first.
+func showUnifiedDiff(files []FileData, rawLines [][]string, context int) {
+	if len(files) < 2 {
+		return
+	}
+
+	if len(files) == 2 {
+		if !printUnifiedDiff(files[0].Path, files[1].Path, rawLines[0], rawLines[1], context) {
+			fmt.Printf("%s and %s are identical\n", files[0].Path, files[1].Path)
+		}
+		return
+	}
+
+	for i := 1; i < len(files); i++ {
+		if !printUnifiedDiff(files[0].Path, files[i].Path, rawLines[0], rawLines[i], context) {
+			fmt.Printf("%s and %s are identical\n", files[0].Path, files[i].Path)
+		}
+		fmt.Println()
+	}
+}
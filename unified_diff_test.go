@@ -0,0 +1,130 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func opsToStrings(ops []diffOp) []string {
+	var out []string
+	for _, op := range ops {
+		out = append(out, string(op.Kind)+op.Line)
+	}
+	return out
+}
+
+func TestMyersDiffIdentical(t *testing.T) {
+	a := []string{"one", "two", "three"}
+	b := []string{"one", "two", "three"}
+
+	ops := myersDiff(a, b)
+	for _, op := range ops {
+		if op.Kind != ' ' {
+			t.Errorf("expected only equal ops for identical input, got %v", opsToStrings(ops))
+			break
+		}
+	}
+}
+
+func TestMyersDiffInsert(t *testing.T) {
+	a := []string{"one", "two", "three"}
+	b := []string{"one", "two", "four", "three"}
+
+	ops := myersDiff(a, b)
+	got := opsToStrings(ops)
+	expected := []string{" one", " two", "+four", " three"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMyersDiffTotallyDifferent(t *testing.T) {
+	a := []string{"apple", "banana"}
+	b := []string{"cherry", "date"}
+
+	ops := myersDiff(a, b)
+	var deletes, inserts int
+	for _, op := range ops {
+		switch op.Kind {
+		case '-':
+			deletes++
+		case '+':
+			inserts++
+		}
+	}
+	if deletes != 2 || inserts != 2 {
+		t.Errorf("expected 2 deletes and 2 inserts, got %d deletes and %d inserts", deletes, inserts)
+	}
+}
+
+func TestMyersDiffEmptyInputs(t *testing.T) {
+	if ops := myersDiff(nil, nil); ops != nil {
+		t.Errorf("expected nil ops for two empty inputs, got %v", ops)
+	}
+
+	ops := myersDiff(nil, []string{"a", "b"})
+	got := opsToStrings(ops)
+	expected := []string{"+a", "+b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestBuildHunksMergesCloseChanges(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7"}
+	b := []string{"1", "X", "3", "4", "Y", "6", "7"}
+
+	ops := myersDiff(a, b)
+	hunks := buildHunks(ops, 3)
+
+	if len(hunks) != 1 {
+		t.Fatalf("expected the two nearby changes to merge into one hunk, got %d hunks", len(hunks))
+	}
+}
+
+func TestBuildHunksSplitsFarApartChanges(t *testing.T) {
+	a := make([]string, 0, 20)
+	b := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		a = append(a, "line")
+		b = append(b, "line")
+	}
+	a[0] = "changed-a"
+	b[0] = "changed-b"
+	a[19] = "changed-a2"
+	b[19] = "changed-b2"
+
+	ops := myersDiff(a, b)
+	hunks := buildHunks(ops, 2)
+
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 separate hunks for far apart changes, got %d", len(hunks))
+	}
+}
+
+func TestBuildHunksZeroLengthSideUsesPreviousLineNumber(t *testing.T) {
+	a := []string{"a", "b", "c"}
+	b := []string{"a", "b", "X", "c"}
+
+	ops := myersDiff(a, b)
+	hunks := buildHunks(ops, 0)
+
+	if len(hunks) != 1 {
+		t.Fatalf("expected 1 hunk, got %d", len(hunks))
+	}
+	h := hunks[0]
+	if h.ALen != 0 || h.AStart != 2 {
+		t.Errorf("expected a pure insert reported as -2,0, got -%d,%d", h.AStart, h.ALen)
+	}
+	if h.BLen != 1 || h.BStart != 3 {
+		t.Errorf("expected the insert at +3,1, got +%d,%d", h.BStart, h.BLen)
+	}
+}
+
+func TestBuildHunksNoChanges(t *testing.T) {
+	ops := myersDiff([]string{"a", "b"}, []string{"a", "b"})
+	hunks := buildHunks(ops, 3)
+	if len(hunks) != 0 {
+		t.Errorf("expected no hunks for identical input, got %d", len(hunks))
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DirCompareResult buckets every relative path found under two directory
+// trees the way rclone's `check` does: present in both (further split
+// into matching and differing) or present in only one side.
+type DirCompareResult struct {
+	SrcRoot, DstRoot string
+	Match            []string
+	Differ           []string
+	MissingOnDst     []string // present under SrcRoot, absent under DstRoot
+	MissingOnSrc     []string // present under DstRoot, absent under SrcRoot
+}
+
+// walkRelativeFiles walks root and returns the set of non-directory
+// entries found, keyed by their slash-separated path relative to root.
+func walkRelativeFiles(root string) (map[string]bool, error) {
+	files := make(map[string]bool)
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// compareDirectories walks srcRoot and dstRoot, pairs files by relative
+// path, and classifies each pair as matching, differing, or missing on
+// one side.
+func compareDirectories(srcRoot, dstRoot string) (DirCompareResult, error) {
+	srcFiles, err := walkRelativeFiles(srcRoot)
+	if err != nil {
+		return DirCompareResult{}, err
+	}
+	dstFiles, err := walkRelativeFiles(dstRoot)
+	if err != nil {
+		return DirCompareResult{}, err
+	}
+
+	result := DirCompareResult{SrcRoot: srcRoot, DstRoot: dstRoot}
+
+	var relPaths []string
+	for rel := range srcFiles {
+		relPaths = append(relPaths, rel)
+	}
+	sort.Strings(relPaths)
+
+	for _, rel := range relPaths {
+		if !dstFiles[rel] {
+			result.MissingOnDst = append(result.MissingOnDst, rel)
+			continue
+		}
+		same, err := filesEqual(filepath.Join(srcRoot, rel), filepath.Join(dstRoot, rel))
+		if err != nil {
+			return DirCompareResult{}, err
+		}
+		if same {
+			result.Match = append(result.Match, rel)
+		} else {
+			result.Differ = append(result.Differ, rel)
+		}
+	}
+
+	var onlyDst []string
+	for rel := range dstFiles {
+		if !srcFiles[rel] {
+			onlyDst = append(onlyDst, rel)
+		}
+	}
+	sort.Strings(onlyDst)
+	result.MissingOnSrc = onlyDst
+
+	return result, nil
+}
+
+// filesEqual reports whether two files have byte-identical content.
+func filesEqual(a, b string) (bool, error) {
+	aBytes, err := os.ReadFile(a)
+	if err != nil {
+		return false, err
+	}
+	bBytes, err := os.ReadFile(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aBytes, bBytes), nil
+}
+
+// showDirCompare prints the summary table and, for every differing pair,
+// a unified diff between the two sides.
+func showDirCompare(result DirCompareResult, context int) {
+	fmt.Printf("Comparing %s -> %s\n\n", result.SrcRoot, result.DstRoot)
+	fmt.Printf("  %d match\n", len(result.Match))
+	fmt.Printf("  %d differ\n", len(result.Differ))
+	fmt.Printf("  %d missing on dst (%s)\n", len(result.MissingOnDst), result.DstRoot)
+	fmt.Printf("  %d missing on src (%s)\n", len(result.MissingOnSrc), result.SrcRoot)
+
+	if len(result.MissingOnDst) > 0 {
+		fmt.Printf("\nOnly in %s:\n", result.SrcRoot)
+		for _, rel := range result.MissingOnDst {
+			fmt.Printf("  %s\n", rel)
+		}
+	}
+	if len(result.MissingOnSrc) > 0 {
+		fmt.Printf("\nOnly in %s:\n", result.DstRoot)
+		for _, rel := range result.MissingOnSrc {
+			fmt.Printf("  %s\n", rel)
+		}
+	}
+
+	if len(result.Differ) == 0 {
+		return
+	}
+
+	fmt.Printf("\nDiffs for files that differ:\n")
+	for _, rel := range result.Differ {
+		srcPath := filepath.Join(result.SrcRoot, rel)
+		dstPath := filepath.Join(result.DstRoot, rel)
+
+		srcLines, err := readLines(srcPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", srcPath, err)
+			continue
+		}
+		dstLines, err := readLines(dstPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", dstPath, err)
+			continue
+		}
+
+		fmt.Println()
+		printUnifiedDiff(srcPath, dstPath, srcLines, dstLines, context)
+	}
+}
+
+// jaccardSimilarity returns |A∩B| / |A∪B| for two line sets, or 1.0 if
+// both sets are empty (two empty files are considered identical).
+func jaccardSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1.0
+	}
+
+	intersection := 0
+	for line := range a {
+		if b[line] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	if union == 0 {
+		return 1.0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// similarityMatrix computes the full pairwise Jaccard similarity matrix
+// for a set of files.
+func similarityMatrix(files []FileData) [][]float64 {
+	matrix := make([][]float64, len(files))
+	for i := range files {
+		matrix[i] = make([]float64, len(files))
+	}
+
+	for i := range files {
+		for j := range files {
+			if i == j {
+				matrix[i][j] = 1.0
+				continue
+			}
+			if j < i {
+				matrix[i][j] = matrix[j][i]
+				continue
+			}
+			matrix[i][j] = jaccardSimilarity(files[i].Lines, files[j].Lines)
+		}
+	}
+
+	return matrix
+}
+
+// clusterBySimilarity groups file indices into clusters using
+// union-find, joining any two files whose pairwise similarity meets or
+// exceeds threshold.
+func clusterBySimilarity(matrix [][]float64, threshold float64) [][]int {
+	n := len(matrix)
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(x int) int {
+		for parent[x] != x {
+			parent[x] = parent[parent[x]]
+			x = parent[x]
+		}
+		return x
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		if ra != rb {
+			parent[ra] = rb
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if matrix[i][j] >= threshold {
+				union(i, j)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := 0; i < n; i++ {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	var clusters [][]int
+	for _, members := range groups {
+		clusters = append(clusters, members)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i][0] < clusters[j][0] })
+
+	return clusters
+}
+
+// showSimilarityMatrix prints the N×N Jaccard similarity matrix and, if
+// clusterThreshold > 0, the resulting similarity clusters.
+func showSimilarityMatrix(files []FileData, clusterThreshold float64) {
+	matrix := similarityMatrix(files)
+
+	fmt.Printf("Jaccard similarity matrix (%d files):\n\n", len(files))
+	fmt.Printf("%-30s", "")
+	for _, file := range files {
+		fmt.Printf("%10s", filepath.Base(file.Path))
+	}
+	fmt.Println()
+
+	for i, file := range files {
+		fmt.Printf("%-30s", filepath.Base(file.Path))
+		for j := range files {
+			fmt.Printf("%10.2f", matrix[i][j])
+		}
+		fmt.Println()
+	}
+
+	if clusterThreshold <= 0 {
+		return
+	}
+
+	clusters := clusterBySimilarity(matrix, clusterThreshold)
+	fmt.Printf("\nClusters at similarity >= %.2f:\n", clusterThreshold)
+	for ci, members := range clusters {
+		fmt.Printf("  Cluster %d:\n", ci+1)
+		for _, idx := range members {
+			fmt.Printf("    %s\n", files[idx].Path)
+		}
+	}
+}
+
+// isDir reports whether path exists and is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// expandGlobs expands any shell-style glob patterns among paths (for
+// shells or callers that pass the pattern through unexpanded). Paths
+// that aren't a directory and don't match as a glob are kept as-is so
+// existing plain-file usage is unaffected.
+func expandGlobs(paths []string) ([]string, error) {
+	var expanded []string
+	for _, p := range paths {
+		if isDir(p) {
+			expanded = append(expanded, p)
+			continue
+		}
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			expanded = append(expanded, p)
+			continue
+		}
+		expanded = append(expanded, matches...)
+	}
+	return expanded, nil
+}
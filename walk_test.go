@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJaccardSimilarity(t *testing.T) {
+	a := map[string]bool{"apple": true, "banana": true}
+	b := map[string]bool{"banana": true, "cherry": true}
+
+	got := jaccardSimilarity(a, b)
+	want := 1.0 / 3.0
+	if got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJaccardSimilarityBothEmpty(t *testing.T) {
+	if got := jaccardSimilarity(map[string]bool{}, map[string]bool{}); got != 1.0 {
+		t.Errorf("expected 1.0 for two empty sets, got %v", got)
+	}
+}
+
+func TestSimilarityMatrixDiagonalIsOne(t *testing.T) {
+	files := []FileData{
+		{Path: "a", Lines: map[string]bool{"x": true}},
+		{Path: "b", Lines: map[string]bool{"y": true}},
+	}
+	matrix := similarityMatrix(files)
+	if matrix[0][0] != 1.0 || matrix[1][1] != 1.0 {
+		t.Errorf("expected diagonal of 1.0, got %v", matrix)
+	}
+	if matrix[0][1] != matrix[1][0] {
+		t.Errorf("expected symmetric matrix, got %v", matrix)
+	}
+}
+
+func TestClusterBySimilarity(t *testing.T) {
+	matrix := [][]float64{
+		{1.0, 0.9, 0.1},
+		{0.9, 1.0, 0.1},
+		{0.1, 0.1, 1.0},
+	}
+
+	clusters := clusterBySimilarity(matrix, 0.8)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %v", len(clusters), clusters)
+	}
+	if !reflect.DeepEqual(clusters[0], []int{0, 1}) {
+		t.Errorf("expected first cluster [0,1], got %v", clusters[0])
+	}
+	if !reflect.DeepEqual(clusters[1], []int{2}) {
+		t.Errorf("expected second cluster [2], got %v", clusters[1])
+	}
+}
+
+func setupDirPair(t *testing.T) (string, string) {
+	t.Helper()
+	srcRoot := t.TempDir()
+	dstRoot := t.TempDir()
+
+	writeFile := func(root, rel, content string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	writeFile(srcRoot, "same.txt", "identical\n")
+	writeFile(dstRoot, "same.txt", "identical\n")
+
+	writeFile(srcRoot, "changed.txt", "old\n")
+	writeFile(dstRoot, "changed.txt", "new\n")
+
+	writeFile(srcRoot, "only_src.txt", "src only\n")
+	writeFile(dstRoot, "only_dst.txt", "dst only\n")
+
+	return srcRoot, dstRoot
+}
+
+func TestCompareDirectories(t *testing.T) {
+	srcRoot, dstRoot := setupDirPair(t)
+
+	result, err := compareDirectories(srcRoot, dstRoot)
+	if err != nil {
+		t.Fatalf("compareDirectories failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Match, []string{"same.txt"}) {
+		t.Errorf("expected Match [same.txt], got %v", result.Match)
+	}
+	if !reflect.DeepEqual(result.Differ, []string{"changed.txt"}) {
+		t.Errorf("expected Differ [changed.txt], got %v", result.Differ)
+	}
+	if !reflect.DeepEqual(result.MissingOnDst, []string{"only_src.txt"}) {
+		t.Errorf("expected MissingOnDst [only_src.txt], got %v", result.MissingOnDst)
+	}
+	if !reflect.DeepEqual(result.MissingOnSrc, []string{"only_dst.txt"}) {
+		t.Errorf("expected MissingOnSrc [only_dst.txt], got %v", result.MissingOnSrc)
+	}
+}
+
+func TestIsDir(t *testing.T) {
+	dir := t.TempDir()
+	if !isDir(dir) {
+		t.Error("expected temp dir to be reported as a directory")
+	}
+	if isDir(filepath.Join(dir, "nonexistent")) {
+		t.Error("expected nonexistent path to not be reported as a directory")
+	}
+}